@@ -0,0 +1,95 @@
+package procedural
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+// NoiseSphere is a sphere whose radius is perturbed by fractal simplex
+// noise sampled from each vertex's direction vector.
+type NoiseSphere struct {
+	ProceduralGeometry
+	radius   float32
+	segments int
+}
+
+// NewNoiseSphere creates a new noise-displaced sphere of the given base
+// radius, subdivided into segments latitude/longitude bands, displaced
+// using the supplied noise parameters.
+func NewNoiseSphere(radius float32, segments int, params Params) *NoiseSphere {
+
+	s := new(NoiseSphere)
+	s.radius = radius
+	s.segments = segments
+	s.ProceduralGeometry.init(params, s.build)
+	return s
+}
+
+func (s *NoiseSphere) build(params Params) (math32.ArrayF32, math32.ArrayF32, math32.ArrayU32) {
+
+	displace := func(dir *math32.Vector3) float32 {
+		n := FBM3(
+			float64(dir.X*params.Frequency),
+			float64(dir.Y*params.Frequency),
+			float64(dir.Z*params.Frequency+params.Time),
+			params.Octaves, 1, 1,
+		)
+		return s.radius * (1 + float32(n)*params.Amplitude)
+	}
+
+	var positions math32.ArrayF32
+	var normals math32.ArrayF32
+
+	latBands := s.segments
+	lonBands := s.segments
+
+	dirAt := func(lat, lon int) *math32.Vector3 {
+		theta := float32(lat) * math32.Pi / float32(latBands)
+		phi := float32(lon) * 2 * math32.Pi / float32(lonBands)
+		return math32.NewVector3(
+			math32.Sin(theta)*math32.Cos(phi),
+			math32.Cos(theta),
+			math32.Sin(theta)*math32.Sin(phi),
+		)
+	}
+
+	for lat := 0; lat <= latBands; lat++ {
+		for lon := 0; lon <= lonBands; lon++ {
+
+			dir := dirAt(lat, lon)
+			r := displace(dir)
+			pos := dir.Clone().MultiplyScalar(r)
+
+			// Approximates the analytic normal from the finite
+			// difference of the radial displacement along the two
+			// tangent directions on the sphere.
+			dirU := dirAt(lat, lon+1)
+			dirV := dirAt(lat+1, lon)
+			posU := dirU.Clone().MultiplyScalar(displace(dirU))
+			posV := dirV.Clone().MultiplyScalar(displace(dirV))
+			tangentU := posU.Clone().Sub(pos)
+			tangentV := posV.Clone().Sub(pos)
+			normal := tangentU.Cross(tangentV)
+			if normal.Dot(dir) < 0 {
+				normal.MultiplyScalar(-1)
+			}
+			normal.Normalize()
+
+			positions.Append(pos.X, pos.Y, pos.Z)
+			normals.Append(normal.X, normal.Y, normal.Z)
+		}
+	}
+
+	var indices math32.ArrayU32
+	stride := lonBands + 1
+	for lat := 0; lat < latBands; lat++ {
+		for lon := 0; lon < lonBands; lon++ {
+			a := uint32(lat*stride + lon)
+			b := uint32(lat*stride + lon + 1)
+			c := uint32((lat+1)*stride + lon)
+			d := uint32((lat+1)*stride + lon + 1)
+			indices.Append(a, c, b, b, c, d)
+		}
+	}
+
+	return positions, normals, indices
+}
@@ -0,0 +1,64 @@
+package procedural
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+// Params holds the noise knobs shared by every procedural geometry.
+type Params struct {
+	Octaves   int
+	Frequency float32
+	Amplitude float32
+	Time      float32
+}
+
+// DefaultParams returns reasonable starting values for gentle displacement.
+func DefaultParams() Params {
+
+	return Params{
+		Octaves:   4,
+		Frequency: 1.0,
+		Amplitude: 0.3,
+		Time:      0,
+	}
+}
+
+// ProceduralGeometry is the base embedded by every geometry in this package.
+type ProceduralGeometry struct {
+	geometry.Geometry
+	params Params
+	build  func(Params) (positions, normals math32.ArrayF32, indices math32.ArrayU32)
+}
+
+// init sets up the base geometry and performs the first build.
+func (pg *ProceduralGeometry) init(params Params, build func(Params) (math32.ArrayF32, math32.ArrayF32, math32.ArrayU32)) {
+
+	pg.Geometry.Init()
+	pg.params = params
+	pg.build = build
+	pg.Rebuild(params)
+}
+
+// Params returns the parameters currently used to build the geometry.
+func (pg *ProceduralGeometry) Params() Params {
+
+	return pg.params
+}
+
+// Rebuild regenerates the geometry's vertex buffers using the supplied
+// parameters, replacing the previous ones. It deletes and re-uploads the
+// VBOs, so it is meant for infrequent, GUI-driven shape changes, not for
+// per-frame animation; animate a displayed surface with a shader uniform
+// instead (see shader.procterrain).
+func (pg *ProceduralGeometry) Rebuild(params Params) {
+
+	pg.params = params
+	positions, normals, indices := pg.build(params)
+
+	pg.Geometry.SetIndices(indices)
+	pg.Geometry.DeleteBuffers()
+	pg.Geometry.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+	pg.Geometry.AddVBO(gls.NewVBO(normals).AddAttrib(gls.VertexNormal))
+}
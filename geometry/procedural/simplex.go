@@ -0,0 +1,236 @@
+// Package procedural provides procedurally generated geometries whose
+// vertices are displaced using a built-in simplex noise implementation.
+package procedural
+
+// simplex2D and simplex3D below implement Ken Perlin's improved simplex
+// noise using the standard skew/unskew factors for 2D and 3D:
+//
+//	F2 = (sqrt(3)-1)/2,  G2 = (3-sqrt(3))/6
+//	F3 = 1/3,            G3 = 1/6
+const (
+	sqrt3 = 1.7320508075688772935 // sqrt(3), kept as a literal since math.Sqrt is not a Go constant expression
+	f2    = (sqrt3 - 1) / 2
+	g2    = (3 - sqrt3) / 6
+	f3    = 1.0 / 3.0
+	g3    = 1.0 / 6.0
+)
+
+// grad3 lists the 12 gradient directions used by the 3D noise (and,
+// restricted to their xy components, by the 2D noise).
+var grad3 = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// perm is the standard permutation table used to hash integer lattice
+// coordinates into a pseudo-random gradient index, duplicated to avoid
+// wrapping when indexing with ij+1.
+var perm [512]int
+
+func init() {
+	p := [256]int{
+		151, 160, 137, 91, 90, 15, 131, 13, 201, 95, 96, 53, 194, 233, 7, 225,
+		140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23, 190, 6, 148,
+		247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32,
+		57, 177, 33, 88, 237, 149, 56, 87, 174, 20, 125, 136, 171, 168, 68, 175,
+		74, 165, 71, 134, 139, 48, 27, 166, 77, 146, 158, 231, 83, 111, 229, 122,
+		60, 211, 133, 230, 220, 105, 92, 41, 55, 46, 245, 40, 244, 102, 143, 54,
+		65, 25, 63, 161, 1, 216, 80, 73, 209, 76, 132, 187, 208, 89, 18, 169,
+		200, 196, 135, 130, 116, 188, 159, 86, 164, 100, 109, 198, 173, 186, 3, 64,
+		52, 217, 226, 250, 124, 123, 5, 202, 38, 147, 118, 126, 255, 82, 85, 212,
+		207, 206, 59, 227, 47, 16, 58, 17, 182, 189, 28, 42, 223, 183, 170, 213,
+		119, 248, 152, 2, 44, 154, 163, 70, 221, 153, 101, 155, 167, 43, 172, 9,
+		129, 22, 39, 253, 19, 98, 108, 110, 79, 113, 224, 232, 178, 185, 112, 104,
+		218, 246, 97, 228, 251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241,
+		81, 51, 145, 235, 249, 14, 239, 107, 49, 192, 214, 31, 181, 199, 106, 157,
+		184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254, 138, 236, 205, 93,
+		222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180,
+	}
+	for i := 0; i < 512; i++ {
+		perm[i] = p[i&255]
+	}
+}
+
+func fastFloor(x float64) int {
+	xi := int(x)
+	if x < float64(xi) {
+		return xi - 1
+	}
+	return xi
+}
+
+func dot2(g [3]float64, x, y float64) float64 {
+	return g[0]*x + g[1]*y
+}
+
+func dot3(g [3]float64, x, y, z float64) float64 {
+	return g[0]*x + g[1]*y + g[2]*z
+}
+
+// Noise2 returns 2D simplex noise in approximately [-1, 1] at (x, y).
+func Noise2(x, y float64) float64 {
+
+	s := (x + y) * f2
+	i := fastFloor(x + s)
+	j := fastFloor(y + s)
+
+	t := float64(i+j) * g2
+	x0 := x - (float64(i) - t)
+	y0 := y - (float64(j) - t)
+
+	var i1, j1 int
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	} else {
+		i1, j1 = 0, 1
+	}
+
+	x1 := x0 - float64(i1) + g2
+	y1 := y0 - float64(j1) + g2
+	x2 := x0 - 1 + 2*g2
+	y2 := y0 - 1 + 2*g2
+
+	ii := i & 255
+	jj := j & 255
+	gi0 := grad3[perm[ii+perm[jj]]%12]
+	gi1 := grad3[perm[ii+i1+perm[jj+j1]]%12]
+	gi2 := grad3[perm[ii+1+perm[jj+1]]%12]
+
+	var n0, n1, n2 float64
+
+	t0 := 0.5 - x0*x0 - y0*y0
+	if t0 >= 0 {
+		t0 *= t0
+		n0 = t0 * t0 * dot2(gi0, x0, y0)
+	}
+
+	t1 := 0.5 - x1*x1 - y1*y1
+	if t1 >= 0 {
+		t1 *= t1
+		n1 = t1 * t1 * dot2(gi1, x1, y1)
+	}
+
+	t2 := 0.5 - x2*x2 - y2*y2
+	if t2 >= 0 {
+		t2 *= t2
+		n2 = t2 * t2 * dot2(gi2, x2, y2)
+	}
+
+	return 70 * (n0 + n1 + n2)
+}
+
+// Noise3 returns 3D simplex noise in approximately [-1, 1] at (x, y, z).
+func Noise3(x, y, z float64) float64 {
+
+	s := (x + y + z) * f3
+	i := fastFloor(x + s)
+	j := fastFloor(y + s)
+	k := fastFloor(z + s)
+
+	t := float64(i+j+k) * g3
+	x0 := x - (float64(i) - t)
+	y0 := y - (float64(j) - t)
+	z0 := z - (float64(k) - t)
+
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+
+	x1 := x0 - float64(i1) + g3
+	y1 := y0 - float64(j1) + g3
+	z1 := z0 - float64(k1) + g3
+	x2 := x0 - float64(i2) + 2*g3
+	y2 := y0 - float64(j2) + 2*g3
+	z2 := z0 - float64(k2) + 2*g3
+	x3 := x0 - 1 + 3*g3
+	y3 := y0 - 1 + 3*g3
+	z3 := z0 - 1 + 3*g3
+
+	ii := i & 255
+	jj := j & 255
+	kk := k & 255
+	gi0 := grad3[perm[ii+perm[jj+perm[kk]]]%12]
+	gi1 := grad3[perm[ii+i1+perm[jj+j1+perm[kk+k1]]]%12]
+	gi2 := grad3[perm[ii+i2+perm[jj+j2+perm[kk+k2]]]%12]
+	gi3 := grad3[perm[ii+1+perm[jj+1+perm[kk+1]]]%12]
+
+	var n0, n1, n2, n3 float64
+
+	t0 := 0.6 - x0*x0 - y0*y0 - z0*z0
+	if t0 >= 0 {
+		t0 *= t0
+		n0 = t0 * t0 * dot3(gi0, x0, y0, z0)
+	}
+
+	t1 := 0.6 - x1*x1 - y1*y1 - z1*z1
+	if t1 >= 0 {
+		t1 *= t1
+		n1 = t1 * t1 * dot3(gi1, x1, y1, z1)
+	}
+
+	t2 := 0.6 - x2*x2 - y2*y2 - z2*z2
+	if t2 >= 0 {
+		t2 *= t2
+		n2 = t2 * t2 * dot3(gi2, x2, y2, z2)
+	}
+
+	t3 := 0.6 - x3*x3 - y3*y3 - z3*z3
+	if t3 >= 0 {
+		t3 *= t3
+		n3 = t3 * t3 * dot3(gi3, x3, y3, z3)
+	}
+
+	return 32 * (n0 + n1 + n2 + n3)
+}
+
+// FBM2 sums `octaves` layers of Noise2 at increasing frequency and
+// decreasing amplitude (each octave doubles the frequency and halves
+// the amplitude), returning a normalized value in approximately [-1, 1].
+func FBM2(x, y float64, octaves int, frequency, amplitude float64) float64 {
+
+	var sum, max float64
+	freq, amp := frequency, amplitude
+	for o := 0; o < octaves; o++ {
+		sum += Noise2(x*freq, y*freq) * amp
+		max += amp
+		freq *= 2
+		amp *= 0.5
+	}
+	if max == 0 {
+		return 0
+	}
+	return sum / max
+}
+
+// FBM3 is the 3D equivalent of FBM2, summing `octaves` layers of Noise3.
+func FBM3(x, y, z float64, octaves int, frequency, amplitude float64) float64 {
+
+	var sum, max float64
+	freq, amp := frequency, amplitude
+	for o := 0; o < octaves; o++ {
+		sum += Noise3(x*freq, y*freq, z*freq) * amp
+		max += amp
+		freq *= 2
+		amp *= 0.5
+	}
+	if max == 0 {
+		return 0
+	}
+	return sum / max
+}
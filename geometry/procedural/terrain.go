@@ -0,0 +1,79 @@
+package procedural
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+// Terrain is a flat plane of segX by segZ quads displaced along Y by
+// fractal simplex noise sampled from each vertex's XZ position.
+type Terrain struct {
+	ProceduralGeometry
+	width, depth float32
+	segX, segZ   int
+}
+
+// NewTerrain creates a new procedural terrain of the given width and
+// depth, subdivided into segX by segZ quads, displaced using the
+// default noise parameters (see DefaultParams).
+func NewTerrain(width, depth float32, segX, segZ int) *Terrain {
+
+	t := new(Terrain)
+	t.width = width
+	t.depth = depth
+	t.segX = segX
+	t.segZ = segZ
+	t.ProceduralGeometry.init(DefaultParams(), t.build)
+	return t
+}
+
+func (t *Terrain) build(params Params) (math32.ArrayF32, math32.ArrayF32, math32.ArrayU32) {
+
+	vertsX := t.segX + 1
+	vertsZ := t.segZ + 1
+
+	height := func(x, z float32) float32 {
+		n := FBM2(float64(x*params.Frequency), float64(z*params.Frequency+params.Time), params.Octaves, 1, 1)
+		return float32(n) * params.Amplitude
+	}
+
+	var positions math32.ArrayF32
+	var normals math32.ArrayF32
+	const eps = 0.01
+
+	for iz := 0; iz < vertsZ; iz++ {
+		for ix := 0; ix < vertsX; ix++ {
+
+			x := (float32(ix)/float32(t.segX) - 0.5) * t.width
+			z := (float32(iz)/float32(t.segZ) - 0.5) * t.depth
+			y := height(x, z)
+
+			// Central differences of the height field, from four extra
+			// noise samples offset by eps along X and Z, give the
+			// gradient the normal is derived from.
+			hx0 := height(x-eps, z)
+			hx1 := height(x+eps, z)
+			hz0 := height(x, z-eps)
+			hz1 := height(x, z+eps)
+			tangentX := math32.NewVector3(2*eps, hx1-hx0, 0)
+			tangentZ := math32.NewVector3(0, hz1-hz0, 2*eps)
+			normal := tangentZ.Cross(tangentX)
+			normal.Normalize()
+
+			positions.Append(x, y, z)
+			normals.Append(normal.X, normal.Y, normal.Z)
+		}
+	}
+
+	var indices math32.ArrayU32
+	for iz := 0; iz < t.segZ; iz++ {
+		for ix := 0; ix < t.segX; ix++ {
+			a := uint32(iz*vertsX + ix)
+			b := uint32(iz*vertsX + ix + 1)
+			c := uint32((iz+1)*vertsX + ix)
+			d := uint32((iz+1)*vertsX + ix + 1)
+			indices.Append(a, c, b, b, c, d)
+		}
+	}
+
+	return positions, normals, indices
+}
@@ -0,0 +1,260 @@
+// Package gltf implements just enough of the binary glTF 2.0 container
+// format (.glb, which a .vrm file also is) to pull a single mesh's
+// positions, normals, indices and morph targets out of it. It is not a
+// general-purpose glTF loader: there is no scene graph, material,
+// texture or skinning support, only what loader/vrm needs to build and
+// blend an avatar's base mesh.
+package gltf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/math32"
+)
+
+const (
+	magic     = 0x46546C67 // "glTF"
+	chunkJSON = 0x4E4F534A // "JSON"
+	chunkBIN  = 0x004E4942 // "BIN\x00"
+)
+
+// Accessor component types used by the meshes this package reads.
+const (
+	componentUnsignedByte  = 5121
+	componentUnsignedShort = 5123
+	componentUnsignedInt   = 5125
+	componentFloat         = 5126
+)
+
+type rawAccessor struct {
+	BufferView    int    `json:"bufferView"`
+	ComponentType int    `json:"componentType"`
+	Count         int    `json:"count"`
+	Type          string `json:"type"`
+}
+
+type rawBufferView struct {
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+}
+
+type rawPrimitive struct {
+	Attributes map[string]int   `json:"attributes"`
+	Indices    int              `json:"indices"`
+	Targets    []map[string]int `json:"targets"`
+}
+
+type rawMesh struct {
+	Primitives []rawPrimitive `json:"primitives"`
+	Extras     struct {
+		TargetNames []string `json:"targetNames"`
+	} `json:"extras"`
+}
+
+type rawDocument struct {
+	Accessors   []rawAccessor   `json:"accessors"`
+	BufferViews []rawBufferView `json:"bufferViews"`
+	Meshes      []rawMesh       `json:"meshes"`
+}
+
+// Document is a parsed .glb file: the JSON scene description plus the
+// single binary buffer chunk its accessors index into.
+type Document struct {
+	raw rawDocument
+	bin []byte
+}
+
+// ParseBin reads and parses the .glb file at path.
+func ParseBin(path string) (*Document, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(data)
+	var header struct {
+		Magic   uint32
+		Version uint32
+		Length  uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("gltf: reading header: %w", err)
+	}
+	if header.Magic != magic {
+		return nil, fmt.Errorf("gltf: not a .glb file (bad magic %#x)", header.Magic)
+	}
+
+	doc := &Document{}
+	for r.Len() > 0 {
+		var chunkLength, chunkType uint32
+		if err := binary.Read(r, binary.LittleEndian, &chunkLength); err != nil {
+			return nil, fmt.Errorf("gltf: reading chunk header: %w", err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &chunkType); err != nil {
+			return nil, fmt.Errorf("gltf: reading chunk header: %w", err)
+		}
+		chunk := make([]byte, chunkLength)
+		if _, err := r.Read(chunk); err != nil {
+			return nil, fmt.Errorf("gltf: reading chunk body: %w", err)
+		}
+		switch chunkType {
+		case chunkJSON:
+			if err := json.Unmarshal(chunk, &doc.raw); err != nil {
+				return nil, fmt.Errorf("gltf: parsing JSON chunk: %w", err)
+			}
+		case chunkBIN:
+			doc.bin = chunk
+		}
+	}
+	return doc, nil
+}
+
+// MeshGeometry builds a geometry.Geometry from mesh meshIdx's first
+// primitive: its POSITION and (if present) NORMAL attributes, and its
+// index buffer.
+func (d *Document) MeshGeometry(meshIdx int) (*geometry.Geometry, error) {
+
+	prim := d.raw.Meshes[meshIdx].Primitives[0]
+
+	posIdx, ok := prim.Attributes["POSITION"]
+	if !ok {
+		return nil, fmt.Errorf("gltf: mesh %d: primitive has no POSITION attribute", meshIdx)
+	}
+	positions, err := d.floats(posIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := d.indices(prim.Indices)
+	if err != nil {
+		return nil, err
+	}
+
+	geom := geometry.NewGeometry()
+	geom.SetIndices(idx)
+	geom.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+
+	if normIdx, ok := prim.Attributes["NORMAL"]; ok {
+		normals, err := d.floats(normIdx)
+		if err != nil {
+			return nil, err
+		}
+		geom.AddVBO(gls.NewVBO(normals).AddAttrib(gls.VertexNormal))
+	}
+	return geom, nil
+}
+
+// MorphTargetNames returns the blendshape names for mesh meshIdx's
+// first primitive, read from the extras.targetNames convention VRM
+// exporters use, falling back to "target0", "target1", ... when absent.
+func (d *Document) MorphTargetNames(meshIdx int) []string {
+
+	mesh := d.raw.Meshes[meshIdx]
+	if len(mesh.Extras.TargetNames) > 0 {
+		return mesh.Extras.TargetNames
+	}
+	names := make([]string, len(mesh.Primitives[0].Targets))
+	for i := range names {
+		names[i] = fmt.Sprintf("target%d", i)
+	}
+	return names
+}
+
+// MorphTargetDeltas returns, for each morph target on mesh meshIdx's
+// first primitive, its POSITION delta buffer. A target with no POSITION
+// delta contributes a nil entry.
+func (d *Document) MorphTargetDeltas(meshIdx int) ([]math32.ArrayF32, error) {
+
+	return d.targetDeltas(meshIdx, "POSITION")
+}
+
+// MorphNormalDeltas mirrors MorphTargetDeltas for each target's NORMAL
+// delta.
+func (d *Document) MorphNormalDeltas(meshIdx int) ([]math32.ArrayF32, error) {
+
+	return d.targetDeltas(meshIdx, "NORMAL")
+}
+
+func (d *Document) targetDeltas(meshIdx int, attribute string) ([]math32.ArrayF32, error) {
+
+	targets := d.raw.Meshes[meshIdx].Primitives[0].Targets
+	deltas := make([]math32.ArrayF32, len(targets))
+	for i, target := range targets {
+		accIdx, ok := target[attribute]
+		if !ok {
+			continue
+		}
+		delta, err := d.floats(accIdx)
+		if err != nil {
+			return nil, err
+		}
+		deltas[i] = delta
+	}
+	return deltas, nil
+}
+
+func vecSize(accessorType string) int {
+	switch accessorType {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC4":
+		return 4
+	default:
+		return 3
+	}
+}
+
+// floats decodes a FLOAT accessor into a flat array of vecSize(Type)
+// components per element.
+func (d *Document) floats(accessorIdx int) (math32.ArrayF32, error) {
+
+	acc := d.raw.Accessors[accessorIdx]
+	if acc.ComponentType != componentFloat {
+		return nil, fmt.Errorf("gltf: accessor %d: unsupported component type %d", accessorIdx, acc.ComponentType)
+	}
+	view := d.raw.BufferViews[acc.BufferView]
+
+	buf := make([]float32, acc.Count*vecSize(acc.Type))
+	r := bytes.NewReader(d.bin[view.ByteOffset : view.ByteOffset+view.ByteLength])
+	if err := binary.Read(r, binary.LittleEndian, &buf); err != nil {
+		return nil, fmt.Errorf("gltf: accessor %d: %w", accessorIdx, err)
+	}
+	return math32.ArrayF32(buf), nil
+}
+
+// indices decodes an UNSIGNED_BYTE/UNSIGNED_SHORT/UNSIGNED_INT accessor
+// into a flat []uint32, widening the narrower component types.
+func (d *Document) indices(accessorIdx int) (math32.ArrayU32, error) {
+
+	acc := d.raw.Accessors[accessorIdx]
+	view := d.raw.BufferViews[acc.BufferView]
+	data := d.bin[view.ByteOffset : view.ByteOffset+view.ByteLength]
+
+	out := make(math32.ArrayU32, acc.Count)
+	switch acc.ComponentType {
+	case componentUnsignedByte:
+		for i := 0; i < acc.Count; i++ {
+			out[i] = uint32(data[i])
+		}
+	case componentUnsignedShort:
+		for i := 0; i < acc.Count; i++ {
+			out[i] = uint32(binary.LittleEndian.Uint16(data[i*2:]))
+		}
+	case componentUnsignedInt:
+		for i := 0; i < acc.Count; i++ {
+			out[i] = binary.LittleEndian.Uint32(data[i*4:])
+		}
+	default:
+		return nil, fmt.Errorf("gltf: accessor %d: unsupported index component type %d", accessorIdx, acc.ComponentType)
+	}
+	return out, nil
+}
@@ -0,0 +1,159 @@
+package vrm
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/renderer"
+
+	g3ndRenderer "github.com/g3n/g3nd/renderer"
+)
+
+// MaxMorphTargets bounds how many blendshapes MorphMaterial can blend in
+// a single draw call; see Avatar.Initialize for what happens to the
+// blendshapes beyond this count.
+const MaxMorphTargets = 4
+
+var morphModules = g3ndRenderer.NewModuleRegistry()
+
+func init() {
+	morphModules.AddShaderModule("attributes", moduleAttributes)
+	morphModules.AddShaderModule("mvp", moduleMVP)
+	morphModules.AddShaderModule("lighting", moduleLighting)
+}
+
+// MorphMaterial blends up to MaxMorphTargets morph-target position and
+// normal deltas into the base vertex position and normal on the GPU,
+// each weighted by its own Influence uniform, so scrubbing a blendshape
+// slider never touches a vertex buffer.
+type MorphMaterial struct {
+	material.Material
+	Influence [MaxMorphTargets]gls.Uniform1f
+}
+
+func newMorphMaterial(r *renderer.Renderer) *MorphMaterial {
+
+	addMorphShader(r, "shaderMorphVertex", sourceMorphVertex)
+	addMorphShader(r, "shaderMorphFrag", sourceMorphFrag)
+	r.AddProgram("progMorph", "shaderMorphVertex", "shaderMorphFrag")
+
+	m := new(MorphMaterial)
+	m.Material.Init()
+	m.SetShader("progMorph")
+
+	for i := range m.Influence {
+		m.Influence[i].Init(fmt.Sprintf("Influence%d", i))
+		m.Influence[i].Set(0)
+	}
+	return m
+}
+
+func (m *MorphMaterial) RenderSetup(gs *gls.GLS) {
+
+	m.Material.RenderSetup(gs)
+	for i := range m.Influence {
+		m.Influence[i].Transfer(gs)
+	}
+}
+
+// addMorphShader expands any #import directives in source against
+// morphModules before registering it with the engine's renderer.
+func addMorphShader(r *renderer.Renderer, name, source string) {
+
+	expanded, err := morphModules.Preprocess(source)
+	if err != nil {
+		panic(err)
+	}
+	r.AddShader(name, expanded)
+}
+
+const moduleAttributes = `
+in vec3 VertexPosition;
+in vec3 VertexNormal;
+in vec2 VertexTexcoord;
+`
+
+const moduleMVP = `
+uniform mat4 MVP;
+uniform mat4 ModelViewMatrix;
+uniform mat4 NormalMatrix;
+`
+
+const moduleLighting = `
+vec3 lambert(vec3 normal, vec3 lightDir, vec3 albedo) {
+	float diffuse = max(dot(normalize(normal), normalize(lightDir)), 0.0);
+	return albedo * diffuse;
+}
+`
+
+// Vertex Shader
+// Sums MorphTarget0..3 position deltas and MorphNormal0..3 normal
+// deltas, each scaled by its own Influence uniform, onto VertexPosition
+// and VertexNormal before projecting; the blended normal is renormalized
+// after transforming it, since the per-target deltas are not unit length.
+const sourceMorphVertex = `
+#version {{.Version}}
+
+#import "attributes"
+#import "mvp"
+
+in vec3 MorphTarget0;
+in vec3 MorphTarget1;
+in vec3 MorphTarget2;
+in vec3 MorphTarget3;
+
+in vec3 MorphNormal0;
+in vec3 MorphNormal1;
+in vec3 MorphNormal2;
+in vec3 MorphNormal3;
+
+uniform float Influence0;
+uniform float Influence1;
+uniform float Influence2;
+uniform float Influence3;
+
+out vec3 vNormal;
+out vec3 vViewPosition;
+
+void main() {
+
+	vec3 position = VertexPosition
+		+ MorphTarget0 * Influence0
+		+ MorphTarget1 * Influence1
+		+ MorphTarget2 * Influence2
+		+ MorphTarget3 * Influence3;
+
+	vec3 normal = VertexNormal
+		+ MorphNormal0 * Influence0
+		+ MorphNormal1 * Influence1
+		+ MorphNormal2 * Influence2
+		+ MorphNormal3 * Influence3;
+
+	vec4 viewPosition = ModelViewMatrix * vec4(position, 1.0);
+	vViewPosition = viewPosition.xyz;
+	vNormal = normalize(mat3(NormalMatrix) * normal);
+	gl_Position = MVP * vec4(position, 1.0);
+}
+
+`
+
+// Fragment Shader
+const sourceMorphFrag = `
+#version {{.Version}}
+
+#import "lighting"
+
+in vec3 vNormal;
+in vec3 vViewPosition;
+
+out vec4 Out_Color;
+
+void main() {
+
+	vec3 lightDir = normalize(vec3(0.3, 0.6, 1.0));
+	vec3 color = lambert(vNormal, lightDir, vec3(0.8, 0.8, 0.85));
+	Out_Color = vec4(color + vec3(0.05), 1.0);
+}
+
+`
@@ -0,0 +1,120 @@
+// Package vrm demonstrates loading a VRM avatar (a glTF extension adding
+// humanoid bone mapping and blendshape metadata on top of a regular
+// glTF scene) and driving its morph targets from the GUI.
+package vrm
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/g3nd/app"
+	"github.com/g3n/g3nd/demos"
+	"github.com/g3n/g3nd/loader/gltf"
+)
+
+func init() {
+	demos.Map["loader.vrm"] = &Avatar{}
+}
+
+// Avatar loads a VRM model's first mesh (via loader/gltf, since
+// github.com/g3n/engine has no glTF loader of its own) and exposes one
+// GUI slider per blendshape, each wired to mat's Influence uniforms so
+// weights are blended on the GPU rather than by re-uploading vertex
+// buffers. Bone-driven skeletal posing is not implemented: only the
+// mesh's base geometry and its morph targets are loaded.
+type Avatar struct {
+	mesh   *graphic.Mesh
+	mat    *MorphMaterial
+	rotate bool
+}
+
+func (t *Avatar) Initialize(a *app.App) {
+
+	// Add help label
+	const help = `VRM avatar with morph-target (blendshape) sliders`
+	label1 := gui.NewLabel(help)
+	label1.SetFontSize(16)
+	label1.SetPosition(10, 10)
+	a.Gui().Add(label1)
+
+	// Adds directional front light
+	dir1 := light.NewDirectional(math32.NewColor(1, 1, 1), 0.8)
+	dir1.SetPosition(0, 1, 1)
+	a.Scene().Add(dir1)
+
+	// Add axis helper
+	axis := graphic.NewAxisHelper(1)
+	a.Scene().Add(axis)
+
+	doc, err := gltf.ParseBin(a.DirData() + "/gltf/avatar.vrm")
+	if err != nil {
+		a.Log().Fatal("Error loading VRM model: %s", err)
+	}
+
+	geom, err := doc.MeshGeometry(0)
+	if err != nil {
+		a.Log().Fatal("Error building mesh geometry: %s", err)
+	}
+
+	// Blending the morph targets is MorphMaterial's job, not the
+	// loader's: the blendshape position and normal deltas loader/gltf
+	// parsed out of the VRM file are uploaded as extra vertex
+	// attributes for that material's shader to read from.
+	posDeltas, err := doc.MorphTargetDeltas(0)
+	if err != nil {
+		a.Log().Fatal("Error reading morph target deltas: %s", err)
+	}
+	normDeltas, err := doc.MorphNormalDeltas(0)
+	if err != nil {
+		a.Log().Fatal("Error reading morph normal deltas: %s", err)
+	}
+	for i := 0; i < len(posDeltas) && i < MaxMorphTargets; i++ {
+		geom.AddVBO(gls.NewVBO(posDeltas[i]).AddAttrib(gls.Attrib{Name: fmt.Sprintf("MorphTarget%d", i), Size: 3}))
+
+		normDelta := normDeltas[i]
+		if len(normDelta) == 0 {
+			normDelta = make(math32.ArrayF32, len(posDeltas[i]))
+		}
+		geom.AddVBO(gls.NewVBO(normDelta).AddAttrib(gls.Attrib{Name: fmt.Sprintf("MorphNormal%d", i), Size: 3}))
+	}
+
+	t.mat = newMorphMaterial(a.Renderer())
+	t.mesh = graphic.NewMesh(geom, t.mat)
+	a.Scene().Add(t.mesh)
+
+	names := doc.MorphTargetNames(0)
+	if len(names) > MaxMorphTargets {
+		a.Log().Warn("VRM avatar has %d blendshapes, only the first %d are wired to sliders", len(names), MaxMorphTargets)
+		names = names[:MaxMorphTargets]
+	}
+
+	if a.Control() == nil {
+		return
+	}
+	t.rotate = true
+	g1 := a.Control().AddGroup("Rotate")
+	cb0 := g1.AddCheckBox("Rotate").SetValue(true)
+	cb0.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.rotate = !t.rotate
+	})
+
+	g2 := a.Control().AddGroup("Blendshapes")
+	for i, name := range names {
+		idx := i
+		slider := g2.AddSlider(name, 0, 1)
+		slider.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+			t.mat.Influence[idx].Set(slider.Value())
+		})
+	}
+}
+
+func (t *Avatar) Render(a *app.App) {
+
+	if t.rotate {
+		t.mesh.AddRotationY(0.005)
+	}
+}
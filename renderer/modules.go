@@ -0,0 +1,118 @@
+// Package renderer implements a small GLSL preprocessor layer on top of
+// the engine's renderer: shader sources may contain `#import` directives
+// that are expanded against a registry of named modules before being
+// handed to the engine's own Renderer.AddShader/AddProgram, which knows
+// nothing about `#import` itself.
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// importRe matches `#import "module/path"` and `#import module::symbol`.
+// The ::symbol suffix is accepted for forward compatibility with
+// per-symbol imports but, for now, still pulls in the whole module.
+var importRe = regexp.MustCompile(`^\s*#import\s+(?:"([^"]+)"|([A-Za-z_][A-Za-z0-9_/]*)(?:::[A-Za-z_][A-Za-z0-9_]*)?)\s*$`)
+
+// ModuleRegistry holds named GLSL modules that shader sources can pull
+// in with `#import`. A single registry is normally shared by every
+// program in an application, registered once up front.
+type ModuleRegistry struct {
+	modules map[string]string
+	fileIDs map[string]int
+	nextID  int
+}
+
+// NewModuleRegistry returns an empty registry.
+func NewModuleRegistry() *ModuleRegistry {
+
+	return &ModuleRegistry{
+		modules: make(map[string]string),
+		fileIDs: make(map[string]int),
+		nextID:  1,
+	}
+}
+
+// AddShaderModule registers a named GLSL module, available to any
+// source passed to Preprocess as `#import "name"`. Each module gets a
+// stable numeric file id, used to rewrite #line markers on expansion so
+// that a compile error in the module points back at the module's own
+// line numbers (see FileName).
+func (r *ModuleRegistry) AddShaderModule(name, source string) {
+
+	r.modules[name] = source
+	if _, ok := r.fileIDs[name]; !ok {
+		r.fileIDs[name] = r.nextID
+		r.nextID++
+	}
+}
+
+// FileName returns the module registered under the given #line file id,
+// or "" for id 0, which always refers to the original, unexpanded
+// shader source passed to Preprocess.
+func (r *ModuleRegistry) FileName(id int) string {
+
+	for name, fid := range r.fileIDs {
+		if fid == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// Preprocess expands every `#import` directive in source, recursively
+// resolving an imported module's own imports. A module already inlined
+// earlier in this same call is skipped on subsequent imports, so a
+// diamond dependency (two imported modules which both import a third)
+// or a source that imports the same module twice only pays for one
+// copy. Each inlined module is wrapped in `#line` directives carrying
+// its registered file id, so a driver error such as "ERROR: 2:14: ..."
+// can be mapped back to FileName(2), line 14 of that module's own
+// source, rather than a line number in the concatenated text.
+func (r *ModuleRegistry) Preprocess(source string) (string, error) {
+
+	return r.expand(source, 0, make(map[string]bool))
+}
+
+func (r *ModuleRegistry) expand(source string, fileID int, seen map[string]bool) (string, error) {
+
+	lines := strings.Split(source, "\n")
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		m := importRe.FindStringSubmatch(line)
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		if seen[name] {
+			// Keeps a blank line in place of the skipped directive so
+			// the following lines keep their original line numbers.
+			out = append(out, "")
+			continue
+		}
+		seen[name] = true
+
+		mod, ok := r.modules[name]
+		if !ok {
+			return "", fmt.Errorf("shader module not found: %q", name)
+		}
+		id := r.fileIDs[name]
+
+		expanded, err := r.expand(mod, id, seen)
+		if err != nil {
+			return "", err
+		}
+
+		out = append(out, fmt.Sprintf("#line 1 %d", id))
+		out = append(out, expanded)
+		out = append(out, fmt.Sprintf("#line %d %d", i+2, fileID))
+	}
+	return strings.Join(out, "\n"), nil
+}
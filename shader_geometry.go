@@ -43,10 +43,13 @@ func (t *ShaderGeometry) Initialize(ctx *Context) {
 	axis := graphic.NewAxisHelper(1)
 	ctx.Scene.Add(axis)
 
+	// Registers the shared GLSL modules consumed via #import below
+	registerShaderModules()
+
 	// Registers shaders and program
-	ctx.Renderer.AddShader("shaderGSDemoVertex", sourceGSDemoVertex)
-	ctx.Renderer.AddShader("shaderGSDemoGeometry", sourceGSDemoGeometry)
-	ctx.Renderer.AddShader("shaderGSDemoFrag", sourceGSDemoFrag)
+	addShader(ctx, "shaderGSDemoVertex", sourceGSDemoVertex)
+	addShader(ctx, "shaderGSDemoGeometry", sourceGSDemoGeometry)
+	addShader(ctx, "shaderGSDemoFrag", sourceGSDemoFrag)
 	ctx.Renderer.AddProgram("progGSDemo", "shaderGSDemoVertex", "shaderGSDemoFrag", "shaderGSDemoGeometry")
 
 	// Creates shared custom material to show normals
@@ -124,9 +127,7 @@ func (t *ShaderGeometry) Render(ctx *Context) {
 	}
 }
 
-//
 // Normals Custom material
-//
 type NormalsMaterial struct {
 	material.Material // Embedded material
 	ShowWireframe     gls.Uniform1i
@@ -160,16 +161,14 @@ func (m *NormalsMaterial) RenderSetup(gs *gls.GLS) {
 	m.ShowFnormal.Transfer(gs)
 }
 
-//
 // Vertex Shader
 // This is pass-through vertex shader which
 // sends its input directly to the geometry shader
 // without any processing.
-//
 const sourceGSDemoVertex = `
 #version {{.Version}}
 
-{{template "attributes" .}}
+#import "attributes"
 
 // Outputs for geometry shader
 out vec3 vnormal;
@@ -182,12 +181,10 @@ void main() {
 
 `
 
-//
 // Geometry Shader
 // This geometry shader receives triangles vertices
 // from the vertex shader and generates lines for
 // wireframe and/or vertex normals and/or face normals.
-//
 const sourceGSDemoGeometry = `
 #version {{.Version}}
 
@@ -195,7 +192,7 @@ layout (triangles) in;
 layout (line_strip, max_vertices = 12) out;
 
 // Model uniforms
-uniform mat4 MVP;
+#import "mvp"
 
 // Inputs from Vertex Shader
 in vec3 vnormal[];
@@ -275,9 +272,7 @@ void main() {
 
 `
 
-//
 // Fragment Shader template
-//
 const sourceGSDemoFrag = `
 #version {{.Version}}
 
@@ -0,0 +1,110 @@
+package main
+
+import (
+	g3ndRenderer "github.com/g3n/g3nd/renderer"
+)
+
+// shaderModules is the registry every demo using #import registers its
+// modules into and resolves its shader sources against. The engine's
+// own Renderer.AddShader has no notion of #import, so addShader below
+// always expands a source through this registry first.
+var shaderModules = g3ndRenderer.NewModuleRegistry()
+
+// registerShaderModules registers the GLSL modules shared by demos that
+// have been migrated to the `#import` preprocessor. Instead of every
+// demo pasting its own copy of the vertex attributes block or the MVP
+// uniforms, a shader source pulls them in with:
+//
+//	#import "attributes"
+//	#import "mvp"
+func registerShaderModules() {
+
+	shaderModules.AddShaderModule("attributes", moduleAttributes)
+	shaderModules.AddShaderModule("mvp", moduleMVP)
+	shaderModules.AddShaderModule("lighting", moduleLighting)
+	shaderModules.AddShaderModule("noise", moduleNoise)
+}
+
+// addShader expands any #import directives in source against
+// shaderModules before registering it with the engine's renderer.
+func addShader(ctx *Context, name, source string) {
+
+	expanded, err := shaderModules.Preprocess(source)
+	if err != nil {
+		panic(err)
+	}
+	ctx.Renderer.AddShader(name, expanded)
+}
+
+const moduleAttributes = `
+in vec3 VertexPosition;
+in vec3 VertexNormal;
+in vec2 VertexTexcoord;
+`
+
+const moduleMVP = `
+uniform mat4 MVP;
+uniform mat4 ModelViewMatrix;
+uniform mat4 NormalMatrix;
+`
+
+const moduleLighting = `
+vec3 lambert(vec3 normal, vec3 lightDir, vec3 albedo) {
+	float diffuse = max(dot(normalize(normal), normalize(lightDir)), 0.0);
+	return albedo * diffuse;
+}
+`
+
+// moduleNoise is a 2D simplex noise implementation (Ashima Arts'
+// well-known formulation) plus a small fbm() helper, so demos can
+// displace geometry from the GPU instead of re-uploading vertex buffers
+// every frame to animate a noise-driven surface.
+const moduleNoise = `
+vec3 permute(vec3 x) {
+	return mod(((x * 34.0) + 1.0) * x, 289.0);
+}
+
+float snoise(vec2 v) {
+	const vec4 C = vec4(0.211324865405187, 0.366025403784439,
+	                    -0.577350269189626, 0.024390243902439);
+	vec2 i  = floor(v + dot(v, C.yy));
+	vec2 x0 = v - i + dot(i, C.xx);
+	vec2 i1 = (x0.x > x0.y) ? vec2(1.0, 0.0) : vec2(0.0, 1.0);
+	vec4 x12 = x0.xyxy + C.xxzz;
+	x12.xy -= i1;
+	i = mod(i, 289.0);
+	vec3 p = permute(permute(i.y + vec3(0.0, i1.y, 1.0)) + i.x + vec3(0.0, i1.x, 1.0));
+	vec3 m = max(0.5 - vec3(dot(x0, x0), dot(x12.xy, x12.xy), dot(x12.zw, x12.zw)), 0.0);
+	m = m * m;
+	m = m * m;
+	vec3 x = 2.0 * fract(p * C.www) - 1.0;
+	vec3 h = abs(x) - 0.5;
+	vec3 ox = floor(x + 0.5);
+	vec3 a0 = x - ox;
+	m *= 1.79284291400159 - 0.85373472095314 * (a0 * a0 + h * h);
+	vec3 g;
+	g.x = a0.x * x0.x + h.x * x0.y;
+	g.yz = a0.yz * x12.xz + h.yz * x12.yw;
+	return 130.0 * dot(m, g);
+}
+
+// fbm sums up to 8 octaves of snoise, normalizing by the total
+// amplitude so the result stays roughly in [-1, 1] regardless of how
+// many octaves are requested.
+float fbm(vec2 p, int octaves) {
+	float sum = 0.0;
+	float amp = 1.0;
+	float freq = 1.0;
+	float norm = 0.0;
+	for (int i = 0; i < 8; i++) {
+		if (i >= octaves) {
+			break;
+		}
+		sum += snoise(p * freq) * amp;
+		norm += amp;
+		freq *= 2.0;
+		amp *= 0.5;
+	}
+	return sum / max(norm, 0.0001);
+}
+`
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/window"
+)
+
+// adjacencyKey identifies an edge by the welded (position-based) ids of
+// its two endpoints, independent of vertex order.
+type adjacencyKey [2]int32
+
+func newAdjacencyKey(a, b int32) adjacencyKey {
+	if a > b {
+		a, b = b, a
+	}
+	return adjacencyKey{a, b}
+}
+
+// buildAdjacency rewrites geom's index buffer from a plain triangle list
+// into a GL_TRIANGLES_ADJACENCY list: 6 indices per triangle (v0, the
+// vertex opposite edge v0-v1 in the neighboring triangle, v1, opposite
+// v1-v2, v2, opposite v2-v0), as required by a geometry shader declared
+// `layout(triangles_adjacency) in`. Neighbors are found by welding
+// vertices that share a position, since flat-shaded meshes like
+// geometry.NewBox duplicate vertices per face (each with its own
+// normal) and would otherwise look like they have no shared edges at
+// all. Edges with no neighbor (mesh boundaries) fall back to the
+// triangle's own opposite vertex.
+func buildAdjacency(geom *geometry.Geometry) {
+
+	positions := geom.VBO(gls.VertexPosition).Buffer()
+	indices := geom.Indices()
+
+	// Welds vertices that occupy (near) the same position into a
+	// shared id so that edges can be matched across duplicated,
+	// per-face vertices. weldID is indexed by vertex id, not by
+	// position in the index buffer, since those two spaces differ for
+	// any indexed mesh.
+	const weldScale = 1000.0
+	welded := make(map[[3]int32]int32, len(positions)/3)
+	weldID := make([]int32, len(positions)/3)
+	for _, vid := range indices {
+		p := int(vid) * 3
+		key := [3]int32{
+			int32(positions[p] * weldScale),
+			int32(positions[p+1] * weldScale),
+			int32(positions[p+2] * weldScale),
+		}
+		id, ok := welded[key]
+		if !ok {
+			id = int32(len(welded))
+			welded[key] = id
+		}
+		weldID[vid] = id
+	}
+
+	type edgeEntry struct {
+		triangle int
+		opposite uint32
+	}
+	edges := make(map[adjacencyKey][]edgeEntry)
+	triCount := len(indices) / 3
+	for t := 0; t < triCount; t++ {
+		v0, v1, v2 := indices[t*3], indices[t*3+1], indices[t*3+2]
+		w0, w1, w2 := weldID[v0], weldID[v1], weldID[v2]
+		edges[newAdjacencyKey(w0, w1)] = append(edges[newAdjacencyKey(w0, w1)], edgeEntry{t, v2})
+		edges[newAdjacencyKey(w1, w2)] = append(edges[newAdjacencyKey(w1, w2)], edgeEntry{t, v0})
+		edges[newAdjacencyKey(w2, w0)] = append(edges[newAdjacencyKey(w2, w0)], edgeEntry{t, v1})
+	}
+
+	opposite := func(key adjacencyKey, triangle int, ownOpposite uint32) uint32 {
+		for _, e := range edges[key] {
+			if e.triangle != triangle {
+				return e.opposite
+			}
+		}
+		return ownOpposite
+	}
+
+	var adjacency math32.ArrayU32
+	for t := 0; t < triCount; t++ {
+		v0, v1, v2 := indices[t*3], indices[t*3+1], indices[t*3+2]
+		w0, w1, w2 := weldID[v0], weldID[v1], weldID[v2]
+		adj01 := opposite(newAdjacencyKey(w0, w1), t, v2)
+		adj12 := opposite(newAdjacencyKey(w1, w2), t, v0)
+		adj20 := opposite(newAdjacencyKey(w2, w0), t, v1)
+		adjacency.Append(v0, adj01, v1, adj12, v2, adj20)
+	}
+
+	geom.SetIndices(adjacency)
+	geom.SetDrawMode(gls.TRIANGLES_ADJACENCY)
+}
+
+type ShaderOutline struct {
+	ctx    *Context
+	mat    *OutlineMaterial
+	box    *graphic.Mesh
+	sphere *graphic.Mesh
+	rotate bool
+}
+
+func init() {
+	TestMap["shader.outline"] = &ShaderOutline{}
+}
+
+func (t *ShaderOutline) Initialize(ctx *Context) {
+
+	// Add help label
+	const help = `Silhouette/outline generated by Geometry Shader from triangle adjacency`
+	label1 := gui.NewLabel(help)
+	label1.SetFontSize(16)
+	label1.SetPosition(10, 10)
+	ctx.Gui.Add(label1)
+
+	// Adds directional front light
+	dir1 := light.NewDirectional(math32.NewColor(1, 1, 1), 0.6)
+	dir1.SetPosition(0, 0, 100)
+	ctx.Scene.Add(dir1)
+
+	// Add axis helper
+	axis := graphic.NewAxisHelper(1)
+	ctx.Scene.Add(axis)
+
+	// Registers shaders and program
+	ctx.Renderer.AddShader("shaderOutlineVertex", sourceOutlineVertex)
+	ctx.Renderer.AddShader("shaderOutlineGeometry", sourceOutlineGeometry)
+	ctx.Renderer.AddShader("shaderOutlineFrag", sourceOutlineFrag)
+	ctx.Renderer.AddProgram("progOutline", "shaderOutlineVertex", "shaderOutlineFrag", "shaderOutlineGeometry")
+
+	// Creates shared outline material
+	t.mat = newOutlineMaterial()
+
+	// Adds box, rewriting its index buffer to GL_TRIANGLES_ADJACENCY
+	boxGeom := geometry.NewBox(1, 1, 1, 1, 1, 1)
+	buildAdjacency(boxGeom)
+	t.mat.Incref()
+	t.box = graphic.NewMesh(boxGeom, t.mat)
+	t.box.SetPosition(-1.2, 0, 0)
+	ctx.Scene.Add(t.box)
+
+	// Adds sphere, rewriting its index buffer to GL_TRIANGLES_ADJACENCY
+	sphereGeom := geometry.NewSphere(0.8, 16, 16, 0, math32.Pi*2, 0, math32.Pi)
+	buildAdjacency(sphereGeom)
+	t.mat.Incref()
+	t.sphere = graphic.NewMesh(sphereGeom, t.mat)
+	t.sphere.SetPosition(1.2, 0, 0)
+	ctx.Scene.Add(t.sphere)
+
+	// Updates the viewport size uniform whenever the window is resized so
+	// that the outline thickness stays constant in screen-space pixels.
+	width, height := ctx.Win.GetSize()
+	t.mat.Viewport.Set(float32(width), float32(height))
+	ctx.Win.Subscribe(window.OnWindowSize, func(evname string, ev interface{}) {
+		w, h := ctx.Win.GetSize()
+		t.mat.Viewport.Set(float32(w), float32(h))
+	})
+
+	// Add controls
+	if ctx.Control == nil {
+		return
+	}
+	t.rotate = true
+	g1 := ctx.Control.AddGroup("Outline")
+	cb0 := g1.AddCheckBox("Rotate").SetValue(true)
+	cb0.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.rotate = !t.rotate
+	})
+	cb1 := g1.AddCheckBox("Crease edges").SetValue(true)
+	cb1.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		if t.mat.ShowCrease.Get() == 0 {
+			t.mat.ShowCrease.Set(1)
+		} else {
+			t.mat.ShowCrease.Set(0)
+		}
+	})
+	s1 := g1.AddSlider("Thickness", 0.4, 3.0)
+	s1.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.Thickness.Set(s1.Value())
+	})
+	s1.SetValue(0.4)
+	s2 := g1.AddSlider("Crease angle", 0.05, 1.0)
+	s2.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.CreaseCos.Set(s2.Value())
+	})
+	s2.SetValue(0.6)
+}
+
+func (t *ShaderOutline) Render(ctx *Context) {
+
+	if t.rotate {
+		t.box.AddRotationY(0.01)
+		t.sphere.AddRotationX(0.008)
+	}
+}
+
+// Outline Custom material
+// Renders a silhouette by emitting line primitives from a geometry shader
+// that receives triangles plus their three adjacent neighbors
+// (GL_TRIANGLES_ADJACENCY) and decides, per edge, whether the edge lies
+// on the silhouette (front/back facing transition relative to the view
+// direction) or on a crease (sharp angle between adjacent face normals).
+type OutlineMaterial struct {
+	material.Material // Embedded material
+	Color             gls.Uniform3f
+	Thickness         gls.Uniform1f
+	Viewport          gls.Uniform2f
+	ShowCrease        gls.Uniform1i
+	CreaseCos         gls.Uniform1f
+}
+
+func newOutlineMaterial() *OutlineMaterial {
+
+	m := new(OutlineMaterial)
+	m.Material.Init()
+	m.SetShader("progOutline")
+
+	// Creates uniforms
+	m.Color.Init("OutlineColor")
+	m.Thickness.Init("Thickness")
+	m.Viewport.Init("Viewport")
+	m.ShowCrease.Init("ShowCrease")
+	m.CreaseCos.Init("CreaseCos")
+
+	// Set uniform's initial values
+	m.Color.Set(0, 0, 0)
+	m.Thickness.Set(1.5)
+	m.Viewport.Set(1280, 720)
+	m.ShowCrease.Set(1)
+	m.CreaseCos.Set(0.6)
+	return m
+}
+
+func (m *OutlineMaterial) RenderSetup(gs *gls.GLS) {
+
+	m.Material.RenderSetup(gs)
+	m.Color.Transfer(gs)
+	m.Thickness.Transfer(gs)
+	m.Viewport.Transfer(gs)
+	m.ShowCrease.Transfer(gs)
+	m.CreaseCos.Transfer(gs)
+}
+
+// Vertex Shader
+// Pass-through vertex shader; per-vertex work (normal transform) is
+// deferred to the geometry shader which has access to the full
+// triangle-with-neighbors needed to find silhouette edges.
+const sourceOutlineVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+
+out vec3 vnormal;
+out vec3 vposition;
+
+void main() {
+
+	vnormal = VertexNormal;
+	vposition = VertexPosition;
+	gl_Position = vec4(VertexPosition, 1.0);
+}
+
+`
+
+// Geometry Shader
+// Takes a triangle plus its three adjacent triangles (6 vertices) and
+// emits a screen-space-expanded quad (as two triangles) for every edge
+// found to be a silhouette edge (one side facing the camera, the other
+// facing away) or, if enabled, a crease edge (the cosine of the angle
+// between the two face normals sharing the edge is below CreaseCos).
+const sourceOutlineGeometry = `
+#version {{.Version}}
+
+layout (triangles_adjacency) in;
+layout (triangle_strip, max_vertices = 12) out;
+
+uniform mat4 MVP;
+uniform mat4 ModelViewMatrix;
+uniform vec2 Viewport;
+uniform float Thickness;
+uniform int ShowCrease;
+uniform float CreaseCos;
+
+in vec3 vnormal[];
+in vec3 vposition[];
+
+out vec4 vertex_color;
+
+uniform vec3 OutlineColor;
+
+vec3 faceNormal(vec3 a, vec3 b, vec3 c) {
+	return normalize(cross(b - a, c - a));
+}
+
+bool facesCamera(vec3 n, vec3 center) {
+	vec3 view = -normalize((ModelViewMatrix * vec4(center, 1.0)).xyz);
+	return dot(mat3(ModelViewMatrix) * n, view) > 0.0;
+}
+
+void emitEdgeQuad(vec3 p0, vec3 p1) {
+
+	vec4 c0 = MVP * vec4(p0, 1.0);
+	vec4 c1 = MVP * vec4(p1, 1.0);
+
+	vec2 s0 = c0.xy / c0.w * Viewport;
+	vec2 s1 = c1.xy / c1.w * Viewport;
+
+	vec2 dir = normalize(s1 - s0);
+	vec2 normal = vec2(-dir.y, dir.x) * Thickness;
+
+	gl_Position = vec4((s0 - normal) / Viewport * c0.w, c0.zw);
+	vertex_color = vec4(OutlineColor, 1.0);
+	EmitVertex();
+
+	gl_Position = vec4((s0 + normal) / Viewport * c0.w, c0.zw);
+	vertex_color = vec4(OutlineColor, 1.0);
+	EmitVertex();
+
+	gl_Position = vec4((s1 - normal) / Viewport * c1.w, c1.zw);
+	vertex_color = vec4(OutlineColor, 1.0);
+	EmitVertex();
+
+	gl_Position = vec4((s1 + normal) / Viewport * c1.w, c1.zw);
+	vertex_color = vec4(OutlineColor, 1.0);
+	EmitVertex();
+
+	EndPrimitive();
+}
+
+void main() {
+
+	// Main triangle: vertices 0, 2, 4. Adjacent triangles share edges
+	// (0,2), (2,4) and (4,0) through vertices 1, 3 and 5 respectively.
+	vec3 p0 = vposition[0];
+	vec3 p1 = vposition[2];
+	vec3 p2 = vposition[4];
+	vec3 mainN = faceNormal(p0, p1, p2);
+	vec3 center = (p0 + p1 + p2) / 3.0;
+	bool mainFacing = facesCamera(mainN, center);
+
+	// Edge p0-p1, neighbor through vertex 1
+	vec3 n0 = faceNormal(p0, vposition[1], p1);
+	bool n0Facing = facesCamera(n0, (p0 + vposition[1] + p1) / 3.0);
+	if (n0Facing != mainFacing || (ShowCrease != 0 && dot(mainN, n0) < CreaseCos)) {
+		emitEdgeQuad(p0, p1);
+	}
+
+	// Edge p1-p2, neighbor through vertex 3
+	vec3 n1 = faceNormal(p1, vposition[3], p2);
+	bool n1Facing = facesCamera(n1, (p1 + vposition[3] + p2) / 3.0);
+	if (n1Facing != mainFacing || (ShowCrease != 0 && dot(mainN, n1) < CreaseCos)) {
+		emitEdgeQuad(p1, p2);
+	}
+
+	// Edge p2-p0, neighbor through vertex 5
+	vec3 n2 = faceNormal(p2, vposition[5], p0);
+	bool n2Facing = facesCamera(n2, (p2 + vposition[5] + p0) / 3.0);
+	if (n2Facing != mainFacing || (ShowCrease != 0 && dot(mainN, n2) < CreaseCos)) {
+		emitEdgeQuad(p2, p0);
+	}
+}
+
+`
+
+// Fragment Shader template
+const sourceOutlineFrag = `
+#version {{.Version}}
+
+in vec4 vertex_color;
+out vec4 Out_Color;
+
+void main() {
+	Out_Color = vertex_color;
+}
+
+`
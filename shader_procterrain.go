@@ -0,0 +1,208 @@
+package main
+
+import (
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+
+	"github.com/g3n/g3nd/geometry/procedural"
+)
+
+type ShaderProcTerrain struct {
+	ctx     *Context
+	terrain *procedural.Terrain
+	mesh    *graphic.Mesh
+	mat     *ProcTerrainMaterial
+	time    float32
+	animate bool
+}
+
+func init() {
+	TestMap["shader.procterrain"] = &ShaderProcTerrain{}
+}
+
+func (t *ShaderProcTerrain) Initialize(ctx *Context) {
+
+	// Add help label
+	const help = `Procedural terrain displaced by simplex noise, normals recomputed in a Geometry Shader`
+	label1 := gui.NewLabel(help)
+	label1.SetFontSize(16)
+	label1.SetPosition(10, 10)
+	ctx.Gui.Add(label1)
+
+	// Adds directional front light
+	dir1 := light.NewDirectional(math32.NewColor(1, 1, 1), 0.6)
+	dir1.SetPosition(0, 1, 1)
+	ctx.Scene.Add(dir1)
+
+	// Add axis helper
+	axis := graphic.NewAxisHelper(1)
+	ctx.Scene.Add(axis)
+
+	registerShaderModules()
+
+	// Registers shaders and program. Noise displacement and the
+	// per-face normal it implies both happen on the GPU (vertex and
+	// geometry shader below), driven by the Time uniform, so animating
+	// the surface never touches the mesh's vertex buffers.
+	addShader(ctx, "shaderProcTerrainVertex", sourceProcTerrainVertex)
+	addShader(ctx, "shaderProcTerrainGeometry", sourceProcTerrainGeometry)
+	addShader(ctx, "shaderProcTerrainFrag", sourceProcTerrainFrag)
+	ctx.Renderer.AddProgram("progProcTerrain", "shaderProcTerrainVertex", "shaderProcTerrainFrag", "shaderProcTerrainGeometry")
+
+	t.mat = newProcTerrainMaterial()
+
+	// The CPU-side mesh only needs to supply XZ sample positions for the
+	// shader's noise function, so it is rebuilt flat once and never again.
+	t.terrain = procedural.NewTerrain(6, 6, 64, 64)
+	t.terrain.Rebuild(procedural.Params{Amplitude: 0})
+	t.mesh = graphic.NewMesh(t.terrain, t.mat)
+	ctx.Scene.Add(t.mesh)
+
+	// Add controls
+	if ctx.Control == nil {
+		return
+	}
+	t.animate = true
+	g1 := ctx.Control.AddGroup("Procedural Terrain")
+	cb0 := g1.AddCheckBox("Animate").SetValue(true)
+	cb0.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.animate = !t.animate
+	})
+	s1 := g1.AddSlider("Amplitude", 0, 1)
+	s1.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.Amplitude.Set(s1.Value())
+	})
+	s1.SetValue(0.3)
+	s2 := g1.AddSlider("Frequency", 0.1, 3)
+	s2.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.Frequency.Set(s2.Value())
+	})
+	s2.SetValue(1.0)
+	s3 := g1.AddSlider("Octaves", 1, 8)
+	s3.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.Octaves.Set(int32(s3.Value()))
+	})
+	s3.SetValue(4)
+}
+
+func (t *ShaderProcTerrain) Render(ctx *Context) {
+
+	if t.animate {
+		t.time += 0.01
+		t.mat.Time.Set(t.time)
+	}
+}
+
+// Procedural Terrain Custom material
+type ProcTerrainMaterial struct {
+	material.Material // Embedded material
+	Amplitude         gls.Uniform1f
+	Frequency         gls.Uniform1f
+	Octaves           gls.Uniform1i
+	Time              gls.Uniform1f
+}
+
+func newProcTerrainMaterial() *ProcTerrainMaterial {
+
+	m := new(ProcTerrainMaterial)
+	m.Material.Init()
+	m.SetShader("progProcTerrain")
+
+	m.Amplitude.Init("Amplitude")
+	m.Frequency.Init("Frequency")
+	m.Octaves.Init("Octaves")
+	m.Time.Init("Time")
+
+	m.Amplitude.Set(0.3)
+	m.Frequency.Set(1.0)
+	m.Octaves.Set(4)
+	m.Time.Set(0)
+	return m
+}
+
+func (m *ProcTerrainMaterial) RenderSetup(gs *gls.GLS) {
+
+	m.Material.RenderSetup(gs)
+	m.Amplitude.Transfer(gs)
+	m.Frequency.Transfer(gs)
+	m.Octaves.Transfer(gs)
+	m.Time.Transfer(gs)
+}
+
+// Vertex Shader
+// Samples fbm noise at each vertex's XZ position, offset by Time, to
+// displace it along Y on the GPU; the geometry shader below then
+// derives the lit normal analytically from the displaced positions.
+const sourceProcTerrainVertex = `
+#version {{.Version}}
+
+#import "attributes"
+#import "noise"
+
+uniform float Amplitude;
+uniform float Frequency;
+uniform int Octaves;
+uniform float Time;
+
+out vec3 vPosition;
+
+void main() {
+
+	float h = fbm(VertexPosition.xz * Frequency + vec2(0.0, Time), Octaves) * Amplitude;
+	vPosition = vec3(VertexPosition.x, h, VertexPosition.z);
+	gl_Position = vec4(vPosition, 1.0);
+}
+
+`
+
+// Geometry Shader
+const sourceProcTerrainGeometry = `
+#version {{.Version}}
+
+layout (triangles) in;
+layout (triangle_strip, max_vertices = 3) out;
+
+#import "mvp"
+
+in vec3 vPosition[];
+
+out vec3 gNormal;
+out vec3 gViewPosition;
+
+void main() {
+
+	vec3 faceNormal = normalize(mat3(NormalMatrix) * cross(vPosition[1] - vPosition[0], vPosition[2] - vPosition[0]));
+
+	for (int i = 0; i < 3; i++) {
+		gl_Position = MVP * vec4(vPosition[i], 1.0);
+		gNormal = faceNormal;
+		gViewPosition = vPosition[i];
+		EmitVertex();
+	}
+	EndPrimitive();
+}
+
+`
+
+// Fragment Shader
+const sourceProcTerrainFrag = `
+#version {{.Version}}
+
+in vec3 gNormal;
+in vec3 gViewPosition;
+
+out vec4 Out_Color;
+
+void main() {
+
+	vec3 lightDir = normalize(vec3(0.3, 0.6, 1.0));
+	float diffuse = max(dot(normalize(gNormal), lightDir), 0.1);
+	vec3 color = vec3(0.35, 0.55, 0.3) * diffuse;
+	Out_Color = vec4(color, 1.0);
+}
+
+`
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/light"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+type ShaderTessellation struct {
+	ctx           *Context
+	patch         *graphic.Mesh
+	mat           *TessMaterial
+	subdivisions  int
+	showWireframe bool
+	rotate        bool
+}
+
+func init() {
+	TestMap["shader.tessellation"] = &ShaderTessellation{}
+}
+
+func (t *ShaderTessellation) Initialize(ctx *Context) {
+
+	// Add help label
+	const help = `Displacement-mapped patch mesh, subdivision level set from the GUI`
+	label1 := gui.NewLabel(help)
+	label1.SetFontSize(16)
+	label1.SetPosition(10, 10)
+	ctx.Gui.Add(label1)
+
+	// Adds directional front light
+	dir1 := light.NewDirectional(math32.NewColor(1, 1, 1), 0.6)
+	dir1.SetPosition(0, 0, 100)
+	ctx.Scene.Add(dir1)
+
+	// Add axis helper
+	axis := graphic.NewAxisHelper(1)
+	ctx.Scene.Add(axis)
+
+	// Registers shaders and program. There is no tessellation control/
+	// evaluation stage here: the engine's Renderer only supports the
+	// vertex/fragment/geometry program shape (AddProgram), so the
+	// subdivision level is instead baked into the patch mesh itself
+	// (see rebuildPatch) and the displacement that a TES would normally
+	// compute per tessellated vertex is done in the vertex shader below.
+	ctx.Renderer.AddShader("shaderTessVertex", sourceTessVertex)
+	ctx.Renderer.AddShader("shaderTessGeom", sourceTessGeom)
+	ctx.Renderer.AddShader("shaderTessFrag", sourceTessFrag)
+	ctx.Renderer.AddProgram("progTess", "shaderTessVertex", "shaderTessFrag", "shaderTessGeom")
+
+	// Creates tessellation material
+	t.mat = newTessMaterial()
+
+	t.ctx = ctx
+	t.subdivisions = 4
+	t.rebuildPatch()
+
+	// Add controls
+	if ctx.Control == nil {
+		return
+	}
+	t.rotate = true
+	g1 := ctx.Control.AddGroup("Tessellation")
+	cb0 := g1.AddCheckBox("Rotate").SetValue(true)
+	cb0.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.rotate = !t.rotate
+	})
+	cb1 := g1.AddCheckBox("Wireframe").SetValue(false)
+	cb1.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.showWireframe = !t.showWireframe
+		if t.showWireframe {
+			t.mat.ShowWireframe.Set(1)
+		} else {
+			t.mat.ShowWireframe.Set(0)
+		}
+	})
+	s1 := g1.AddSlider("Displacement", 0, 1)
+	s1.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.mat.Displacement.Set(s1.Value())
+	})
+	s1.SetValue(0.3)
+	s2 := g1.AddSlider("Subdivisions", 1, 24)
+	s2.Subscribe(gui.OnChange, func(evname string, ev interface{}) {
+		t.subdivisions = int(s2.Value())
+		t.rebuildPatch()
+	})
+	s2.SetValue(4)
+}
+
+// rebuildPatch replaces the patch mesh's geometry with a plane
+// subdivided into t.subdivisions by t.subdivisions quads. Changing the
+// subdivision level is a rare, GUI-driven action, not a per-frame one,
+// so rebuilding the geometry here is fine.
+func (t *ShaderTessellation) rebuildPatch() {
+
+	rotation := float32(0)
+	if t.patch != nil {
+		rotation = t.patch.Rotation().Z
+		t.ctx.Scene.Remove(t.patch)
+	}
+
+	patchGeom := geometry.NewPlane(4, 4, t.subdivisions, t.subdivisions)
+	t.patch = graphic.NewMesh(patchGeom, t.mat)
+	t.patch.SetRotationX(-math32.Pi / 3)
+	t.patch.SetRotationZ(rotation)
+	t.ctx.Scene.Add(t.patch)
+}
+
+func (t *ShaderTessellation) Render(ctx *Context) {
+
+	if t.rotate {
+		t.patch.AddRotationZ(0.005)
+	}
+}
+
+// Tessellation Custom material
+type TessMaterial struct {
+	material.Material // Embedded material
+	Displacement      gls.Uniform1f
+	ShowWireframe     gls.Uniform1i
+}
+
+func newTessMaterial() *TessMaterial {
+
+	m := new(TessMaterial)
+	m.Material.Init()
+	m.SetShader("progTess")
+
+	// Creates uniforms
+	m.Displacement.Init("Displacement")
+	m.ShowWireframe.Init("ShowWireframe")
+
+	// Set uniform's initial values
+	m.Displacement.Set(0.3)
+	m.ShowWireframe.Set(0)
+	return m
+}
+
+func (m *TessMaterial) RenderSetup(gs *gls.GLS) {
+
+	m.Material.RenderSetup(gs)
+	m.Displacement.Transfer(gs)
+	m.ShowWireframe.Transfer(gs)
+}
+
+// Vertex Shader
+// Displaces each vertex along its normal using a cheap hash-based noise
+// function, standing in for what a tessellation evaluation shader would
+// otherwise compute per generated vertex.
+const sourceTessVertex = `
+#version {{.Version}}
+
+{{template "attributes" .}}
+
+uniform float Displacement;
+
+out vec3 vertPosition;
+out vec3 vertNormal;
+
+float hashNoise(vec3 p) {
+	return fract(sin(dot(p, vec3(12.9898, 78.233, 37.719))) * 43758.5453);
+}
+
+void main() {
+
+	float displace = (hashNoise(VertexPosition * 4.0) - 0.5) * Displacement;
+	vertPosition = VertexPosition + VertexNormal * displace;
+	vertNormal = VertexNormal;
+}
+
+`
+
+// Geometry Shader
+// Forwards each triangle unchanged and, when ShowWireframe is enabled,
+// lets the fragment shader draw its edges from the barycentric
+// coordinates emitted here. The line-strip wireframe pass of
+// shader.geometry is not used.
+const sourceTessGeom = `
+#version {{.Version}}
+
+layout (triangles) in;
+layout (triangle_strip, max_vertices = 3) out;
+
+uniform mat4 MVP;
+
+in vec3 vertPosition[];
+in vec3 vertNormal[];
+
+out vec3 gNormal;
+out vec3 gBary;
+
+void main() {
+
+	vec3 bary[3] = vec3[3](vec3(1, 0, 0), vec3(0, 1, 0), vec3(0, 0, 1));
+
+	for (int i = 0; i < 3; i++) {
+		gl_Position = MVP * vec4(vertPosition[i], 1.0);
+		gNormal = vertNormal[i];
+		gBary = bary[i];
+		EmitVertex();
+	}
+	EndPrimitive();
+}
+
+`
+
+// Fragment Shader
+// Shades the surface with simple Lambertian lighting and, when
+// ShowWireframe is enabled, overlays triangle edges computed from the
+// barycentric coordinates emitted by the geometry shader.
+const sourceTessFrag = `
+#version {{.Version}}
+
+uniform int ShowWireframe;
+
+in vec3 gNormal;
+in vec3 gBary;
+
+out vec4 Out_Color;
+
+void main() {
+
+	vec3 lightDir = normalize(vec3(0.3, 0.6, 1.0));
+	float diffuse = max(dot(normalize(gNormal), lightDir), 0.15);
+	vec3 color = vec3(0.6, 0.7, 0.9) * diffuse;
+
+	if (ShowWireframe != 0) {
+		float edge = min(gBary.x, min(gBary.y, gBary.z));
+		float line = smoothstep(0.0, 0.02, edge);
+		color = mix(vec3(1.0, 1.0, 0.0), color, line);
+	}
+
+	Out_Color = vec4(color, 1.0);
+}
+
+`
@@ -14,37 +14,50 @@ func init() {
 	demos.Map["skybox.sanfrancisco"] = &Skybox{}
 }
 
+// faces are the six cubemap images, in +X,-X,+Y,-Y,+Z,-Z order.
+var faces = []string{
+	"sanfrancisco/posx.jpg",
+	"sanfrancisco/negx.jpg",
+	"sanfrancisco/posy.jpg",
+	"sanfrancisco/negy.jpg",
+	"sanfrancisco/posz.jpg",
+	"sanfrancisco/negz.jpg",
+}
+
+// Skybox is a large inward-facing box, one separately textured material
+// group per face, built from material.Standard and texture.Texture2D
+// (there is no dedicated graphic.Skybox type or image-based lighting
+// support in github.com/g3n/engine yet).
 type Skybox struct {
+	std *material.Standard
 }
 
 func (t *Skybox) Initialize(a *app.App) {
 
-	var textures = []string{
-		"sanfrancisco/posx.jpg",
-		"sanfrancisco/negx.jpg",
-		"sanfrancisco/posy.jpg",
-		"sanfrancisco/negy.jpg",
-		"sanfrancisco/posz.jpg",
-		"sanfrancisco/negz.jpg",
-	}
-
 	// Add axis helper
 	axis := graphic.NewAxisHelper(2)
 	a.Scene().Add(axis)
 
-	geom := geometry.NewBox(50, 50, 50, 2, 2, 2)
-	skybox := graphic.NewMesh(geom, nil)
-	for i := 0; i < 6; i++ {
-		tex, err := texture.NewTexture2DFromImage(a.DirData() + "/images/" + textures[i])
+	// Large inward-facing box, one textured material group per face.
+	boxGeom := geometry.NewBox(50, 50, 50, 2, 2, 2)
+	box := graphic.NewMesh(boxGeom, nil)
+	for i, face := range faces {
+		tex, err := texture.NewTexture2DFromImage(a.DirData() + "/images/" + face)
 		if err != nil {
 			a.Log().Fatal("Error loading texture: %s", err)
 		}
 		matFace := material.NewStandard(&math32.Color{1, 1, 1})
 		matFace.AddTexture(tex)
 		matFace.SetSide(material.SideBack)
-		skybox.AddGroupMaterial(matFace, i)
+		box.AddGroupMaterial(matFace, i)
 	}
-	a.Scene().Add(skybox)
+	a.Scene().Add(box)
+
+	// A standard-lit sphere at the center of the box.
+	t.std = material.NewStandard(&math32.Color{1, 1, 1})
+	sphereGeom := geometry.NewSphere(4, 32, 32, 0, math32.Pi*2, 0, math32.Pi)
+	sphere := graphic.NewMesh(sphereGeom, t.std)
+	a.Scene().Add(sphere)
 }
 
 func (t *Skybox) Render(a *app.App) {